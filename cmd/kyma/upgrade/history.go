@@ -0,0 +1,46 @@
+package upgrade
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/spf13/cobra"
+)
+
+// NewHistoryCmd creates the "upgrade history" command.
+func NewHistoryCmd() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Lists recorded upgrade snapshots.",
+		Long:  "Use this command to list the upgrade snapshots available to \"kyma upgrade rollback\", most recent first.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runHistory(o)
+		},
+	}
+
+	addKubeFlags(cmd, o)
+
+	return cmd
+}
+
+func runHistory(o *options) error {
+	i := &installation.Installation{Options: o.installationOptions()}
+	history, err := i.UpgradeHistory()
+	if err != nil {
+		return err
+	}
+	printHistory(history)
+	return nil
+}
+
+func printHistory(history []installation.UpgradeSnapshot) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tTIMESTAMP\tFROM\tTO")
+	for _, snapshot := range history {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", snapshot.Key, snapshot.Timestamp.Format("2006-01-02 15:04:05"), snapshot.FromVersion, snapshot.ToVersion)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,33 @@
+package upgrade
+
+import (
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/spf13/cobra"
+)
+
+// NewPlanCmd creates the "upgrade plan" command.
+func NewPlanCmd() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Shows whether Kyma on the cluster can be upgraded, without changing anything.",
+		Long:  "Use this command to run the same preflight checks as \"kyma upgrade\" and see the planned upgrade, without triggering it.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runPlan(o)
+		},
+	}
+
+	addCommonFlags(cmd, o)
+
+	return cmd
+}
+
+func runPlan(o *options) error {
+	i := &installation.Installation{Options: o.installationOptions()}
+	result, err := i.PlanUpgrade()
+	if err != nil {
+		return err
+	}
+	installation.PrintPlanToStdout(result)
+	return nil
+}
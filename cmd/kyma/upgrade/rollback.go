@@ -0,0 +1,33 @@
+package upgrade
+
+import (
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/spf13/cobra"
+)
+
+// NewRollbackCmd creates the "upgrade rollback" command.
+func NewRollbackCmd() *cobra.Command {
+	o := &options{}
+	var key string
+	cmd := &cobra.Command{
+		Use:   "rollback [snapshot-key]",
+		Short: "Rolls back to a previously recorded upgrade snapshot.",
+		Long:  "Use this command to restore the Installer CR and overrides from a snapshot taken before a previous upgrade and re-trigger the installer against it. If no snapshot key is given, the most recent snapshot is used.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				key = args[0]
+			}
+			return runRollback(o, key)
+		},
+	}
+
+	addKubeFlags(cmd, o)
+
+	return cmd
+}
+
+func runRollback(o *options, key string) error {
+	i := &installation.Installation{Options: o.installationOptions()}
+	return i.RollbackUpgrade(key)
+}
@@ -0,0 +1,101 @@
+package upgrade
+
+import (
+	"time"
+
+	"github.com/kyma-project/cli/pkg/installation"
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	kubeconfigPath   string
+	timeout          time.Duration
+	noWait           bool
+	ci               bool
+	nonInteractive   bool
+	componentsConfig string
+	overrideConfigs  []string
+	domain           string
+	tlsCert          string
+	tlsKey           string
+	password         string
+	modules          []string
+	dryRun           bool
+	dryRunDir        string
+	backend          string
+}
+
+// NewCmd creates the "upgrade" command and all of its subcommands.
+func NewCmd() *cobra.Command {
+	o := &options{}
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrades Kyma on a running cluster.",
+		Long:  "Use this command to upgrade Kyma on a running cluster to the version matching the CLI.",
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runUpgrade(o)
+		},
+	}
+
+	addCommonFlags(cmd, o)
+	cmd.AddCommand(NewPlanCmd())
+	cmd.AddCommand(NewRollbackCmd())
+	cmd.AddCommand(NewHistoryCmd())
+
+	return cmd
+}
+
+// addKubeFlags registers the flags every subcommand needs to reach the
+// cluster, since they all initialize their own Kubernetes client.
+func addKubeFlags(cmd *cobra.Command, o *options) {
+	cmd.Flags().StringVarP(&o.kubeconfigPath, "kubeconfig", "k", "", "Path to the kubeconfig file. If empty, the default kubeconfig location is used.")
+	cmd.Flags().DurationVarP(&o.timeout, "timeout", "t", 30*time.Minute, "Maximum time for the upgrade to finish.")
+}
+
+// addCommonFlags registers addKubeFlags plus every flag "kyma upgrade" and
+// "kyma upgrade plan" read to run the preflight pipeline and trigger the
+// upgrade. It is not used by "rollback"/"history", which only read a
+// Kubernetes client's worth of Options and would otherwise expose flags
+// they never consume.
+func addCommonFlags(cmd *cobra.Command, o *options) {
+	addKubeFlags(cmd, o)
+	cmd.Flags().BoolVar(&o.noWait, "noWait", false, "Do not wait for the upgrade to finish.")
+	cmd.Flags().BoolVar(&o.ci, "ci", false, "Enables the CI mode to run on CI/CD systems. It avoids any user interaction (such as no dialog prompts) and ensures that logs are formatted properly in log files (such as no ANSI colors, no spinners).")
+	cmd.Flags().BoolVar(&o.nonInteractive, "non-interactive", false, "Enables the non-interactive shell mode.")
+	cmd.Flags().StringVarP(&o.componentsConfig, "components", "c", "", "Path to a YAML file with component list to override.")
+	cmd.Flags().StringSliceVarP(&o.overrideConfigs, "override", "o", nil, "Path to a YAML file with parameters to override. Multiple entries of this flag are possible.")
+	cmd.Flags().StringVar(&o.domain, "domain", "", "Custom domain for the cluster.")
+	cmd.Flags().StringVar(&o.tlsCert, "tlsCert", "", "TLS certificate for the domain used for installation.")
+	cmd.Flags().StringVar(&o.tlsKey, "tlsKey", "", "TLS key for the domain used for installation.")
+	cmd.Flags().StringVar(&o.password, "password", "", "Predefined cluster password.")
+	cmd.Flags().StringSliceVar(&o.modules, "module", nil, "Upgrade only the given module, in the \"name\" or \"name@version\" form. Can be repeated to upgrade more than one module.")
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", false, "Renders the manifests that would be applied to the cluster, without changing anything.")
+	cmd.Flags().StringVar(&o.dryRunDir, "dry-run-dir", "", "Directory to write --dry-run output to. If empty, the output is printed to stdout.")
+	cmd.Flags().StringVar(&o.backend, "backend", installation.BackendInstaller, "Backend used to apply the upgrade: \"installer\" (through the Kyma Installer CR) or \"helm\" (as plain Helm releases).")
+}
+
+func (o *options) installationOptions() *installation.Options {
+	return &installation.Options{
+		KubeconfigPath:   o.kubeconfigPath,
+		Timeout:          o.timeout,
+		NoWait:           o.noWait,
+		CI:               o.ci,
+		NonInteractive:   o.nonInteractive,
+		ComponentsConfig: o.componentsConfig,
+		OverrideConfigs:  o.overrideConfigs,
+		Domain:           o.domain,
+		TLSCert:          o.tlsCert,
+		TLSKey:           o.tlsKey,
+		Password:         o.password,
+		Modules:          o.modules,
+		DryRun:           o.dryRun,
+		DryRunDir:        o.dryRunDir,
+		Backend:          o.backend,
+	}
+}
+
+func runUpgrade(o *options) error {
+	i := &installation.Installation{Options: o.installationOptions()}
+	_, err := i.UpgradeKyma()
+	return err
+}
@@ -0,0 +1,57 @@
+package installation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	pkgErrors "github.com/pkg/errors"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// writeDryRunOutput renders the Tiller YAML, Installer YAML, Installer CR and
+// the merged configuration that triggerInstallation/triggerUpgrade would send
+// to the cluster, without ever calling the installer. When Options.DryRunDir
+// is empty the rendered output is printed to stdout instead, mirroring
+// kubeadm's "--dry-run" behavior.
+func (i *Installation) writeDryRunOutput(files map[string]*File, configuration installationSDK.Configuration) error {
+	rendered := map[string]string{
+		tillerFile:      files[tillerFile].StringContent,
+		installerFile:   files[installerFile].StringContent,
+		installerCRFile: files[installerCRFile].StringContent,
+	}
+
+	configurationYAML, err := k8syaml.Marshal(configuration)
+	if err != nil {
+		return pkgErrors.Wrap(err, "unable to render the dry-run configuration as YAML")
+	}
+
+	if i.Options.DryRunDir == "" {
+		for _, name := range []string{tillerFile, installerFile, installerCRFile} {
+			fmt.Printf("---\n# %s\n%s\n", name, rendered[name])
+		}
+		fmt.Printf("---\n# configuration\n%s\n", configurationYAML)
+		return nil
+	}
+
+	if err := os.MkdirAll(i.Options.DryRunDir, 0755); err != nil {
+		return fmt.Errorf("Could not create dry-run output directory: %s", err.Error())
+	}
+
+	for name, content := range rendered {
+		dst := filepath.Join(i.Options.DryRunDir, name)
+		if err := ioutil.WriteFile(dst, []byte(content), 0644); err != nil {
+			return fmt.Errorf("Could not write dry-run output file %s: %s", dst, err.Error())
+		}
+	}
+
+	configurationDst := filepath.Join(i.Options.DryRunDir, "configuration.yaml")
+	if err := ioutil.WriteFile(configurationDst, configurationYAML, 0644); err != nil {
+		return fmt.Errorf("Could not write dry-run configuration file %s: %s", configurationDst, err.Error())
+	}
+
+	i.currentStep.LogInfof("Dry-run output written to %s", i.Options.DryRunDir)
+	return nil
+}
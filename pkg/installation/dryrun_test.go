@@ -0,0 +1,25 @@
+package installation
+
+import (
+	"testing"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteDryRunOutput_Stdout(t *testing.T) {
+	i := &Installation{Options: &Options{}}
+	files := map[string]*File{
+		tillerFile:      {StringContent: "tiller: yaml"},
+		installerFile:   {StringContent: "installer: yaml"},
+		installerCRFile: {StringContent: "installerCR: yaml"},
+	}
+	configuration := installationSDK.Configuration{
+		Configuration: installationSDK.ConfigEntries{
+			{Key: "global.domainName", Value: "test.kyma"},
+		},
+	}
+
+	err := i.writeDryRunOutput(files, configuration)
+	require.NoError(t, err)
+}
@@ -0,0 +1,180 @@
+package installation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/kyma/components/installer/pkg/apis/installer/v1alpha1"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// BackendInstaller drives the upgrade through the Kyma Installer CR, as before.
+	BackendInstaller = "installer"
+	// BackendHelm drives the upgrade by rendering and applying each component as a
+	// plain Helm release, bypassing the deprecated Kyma Installer entirely. It is
+	// the supported migration path off the Installer.
+	BackendHelm = "helm"
+)
+
+// triggerHelmUpgrade upgrades componentList as individual Helm releases instead
+// of going through the Kyma Installer CR. Components are upgraded in the order
+// of componentList: the order of the components configuration, or the order of
+// the "--module" flags when modules were requested (see
+// filterComponentsByModules). A release that fails to upgrade is rolled back immediately so
+// a broken component is never left mid-upgrade.
+func (i *Installation) triggerHelmUpgrade(componentList []v1alpha1.KymaComponent, configuration installationSDK.Configuration) error {
+	for _, component := range componentList {
+		valuesFile, err := i.writeHelmValuesFile(component.Name, configuration)
+		if err != nil {
+			return fmt.Errorf("Could not prepare Helm values for component %s: %s", component.Name, err.Error())
+		}
+		defer os.Remove(valuesFile)
+
+		i.currentStep.LogInfof("Upgrading component %s via helm upgrade --install", component.Name)
+		if err := i.helmUpgradeInstall(component, valuesFile); err != nil {
+			i.currentStep.LogInfof("helm upgrade of %s failed, rolling back: %s", component.Name, err.Error())
+			if rbErr := i.helmRollback(component); rbErr != nil {
+				return fmt.Errorf("Helm upgrade of component %s failed (%s) and rollback also failed: %s", component.Name, err.Error(), rbErr.Error())
+			}
+			return fmt.Errorf("Helm upgrade of component %s failed and was rolled back to the previous release: %s", component.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// writeHelmDryRunOutput renders the Helm values that triggerHelmUpgrade would
+// use for every component, without ever invoking helm, mirroring the
+// Installer CR's dry-run behavior for the helm backend.
+func (i *Installation) writeHelmDryRunOutput(componentList []v1alpha1.KymaComponent, configuration installationSDK.Configuration) error {
+	for _, component := range componentList {
+		values, err := helmValuesForComponent(configuration, component.Name)
+		if err != nil {
+			return fmt.Errorf("Could not translate overrides for component %s: %s", component.Name, err.Error())
+		}
+		content, err := yaml.Marshal(values)
+		if err != nil {
+			return fmt.Errorf("Could not render Helm values for component %s: %s", component.Name, err.Error())
+		}
+
+		if i.Options.DryRunDir == "" {
+			fmt.Printf("---\n# %s values.yaml\n%s\n", component.Name, string(content))
+			continue
+		}
+
+		if err := os.MkdirAll(i.Options.DryRunDir, 0755); err != nil {
+			return fmt.Errorf("Could not create dry-run output directory: %s", err.Error())
+		}
+		dst := filepath.Join(i.Options.DryRunDir, fmt.Sprintf("%s.values.yaml", component.Name))
+		if err := ioutil.WriteFile(dst, content, 0644); err != nil {
+			return fmt.Errorf("Could not write dry-run values file %s: %s", dst, err.Error())
+		}
+	}
+
+	if i.Options.DryRunDir != "" {
+		i.currentStep.LogInfof("Dry-run Helm values written to %s", i.Options.DryRunDir)
+	}
+	return nil
+}
+
+// helmChartRef resolves the chart "helm upgrade --install" fetches for a
+// component: the source ref the components configuration (or a pinned
+// "--module name@version", see filterComponentsByModules) set on
+// component.Source, falling back to the bare component name only for a
+// chart that is already registered as a local Helm repo/path.
+func helmChartRef(component v1alpha1.KymaComponent) string {
+	if component.Source != nil && component.Source.URL != "" {
+		return component.Source.URL
+	}
+	return component.Name
+}
+
+func componentNamespace(component v1alpha1.KymaComponent) string {
+	if component.Namespace == "" {
+		return "kyma-system"
+	}
+	return component.Namespace
+}
+
+func (i *Installation) helmUpgradeInstall(component v1alpha1.KymaComponent, valuesFile string) error {
+	args := []string{
+		"upgrade", component.Name, helmChartRef(component),
+		"--install",
+		"--namespace", componentNamespace(component),
+		"--values", valuesFile,
+		"--history-max", "10",
+		"--timeout", i.Options.Timeout.String(),
+	}
+	out, err := exec.Command("helm", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err.Error(), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (i *Installation) helmRollback(component v1alpha1.KymaComponent) error {
+	args := []string{"rollback", component.Name, "--namespace", componentNamespace(component)}
+	out, err := exec.Command("helm", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", err.Error(), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (i *Installation) writeHelmValuesFile(componentName string, configuration installationSDK.Configuration) (string, error) {
+	values, err := helmValuesForComponent(configuration, componentName)
+	if err != nil {
+		return "", err
+	}
+	content, err := yaml.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	f, err := ioutil.TempFile("", fmt.Sprintf("kyma-%s-values-*.yaml", componentName))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(content); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// helmValuesForComponent expands the global and per-component override
+// entries from configuration into the nested map shape "helm upgrade --values"
+// expects, e.g. "global.domainName" becomes {"global": {"domainName": ...}}.
+func helmValuesForComponent(configuration installationSDK.Configuration, componentName string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, entry := range configuration.Configuration {
+		setNestedValue(values, entry.Key, entry.Value)
+	}
+	for _, componentConfiguration := range configuration.ComponentConfiguration {
+		if componentConfiguration.Component != componentName {
+			continue
+		}
+		for _, entry := range componentConfiguration.Configuration {
+			setNestedValue(values, entry.Key, entry.Value)
+		}
+	}
+	return values, nil
+}
+
+func setNestedValue(values map[string]interface{}, dottedKey string, value interface{}) {
+	parts := strings.Split(dottedKey, ".")
+	current := values
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := current[part].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[part] = next
+		}
+		current = next
+	}
+	current[parts[len(parts)-1]] = value
+}
@@ -0,0 +1,83 @@
+package installation
+
+import (
+	"testing"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/kyma/components/installer/pkg/apis/installer/v1alpha1"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetNestedValue(t *testing.T) {
+	values := map[string]interface{}{}
+	setNestedValue(values, "global.domainName", "test.kyma")
+	setNestedValue(values, "global.proxy.resources.requests.cpu", "490m")
+
+	require.Equal(t, map[string]interface{}{
+		"global": map[string]interface{}{
+			"domainName": "test.kyma",
+			"proxy": map[string]interface{}{
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{
+						"cpu": "490m",
+					},
+				},
+			},
+		},
+	}, values)
+}
+
+func Test_HelmValuesForComponent(t *testing.T) {
+	configuration := installationSDK.Configuration{
+		Configuration: installationSDK.ConfigEntries{
+			{Key: "global.domainName", Value: "test.kyma"},
+		},
+		ComponentConfiguration: []installationSDK.ComponentConfiguration{
+			{
+				Component: "istio",
+				Configuration: installationSDK.ConfigEntries{
+					{Key: "global.proxy.resources.requests.cpu", Value: "490m"},
+				},
+			},
+		},
+	}
+
+	values, err := helmValuesForComponent(configuration, "istio")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"global": map[string]interface{}{
+			"domainName": "test.kyma",
+			"proxy": map[string]interface{}{
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{
+						"cpu": "490m",
+					},
+				},
+			},
+		},
+	}, values)
+
+	values, err = helmValuesForComponent(configuration, "monitoring")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"global": map[string]interface{}{
+			"domainName": "test.kyma",
+		},
+	}, values)
+}
+
+func Test_HelmChartRef(t *testing.T) {
+	require.Equal(t, "monitoring", helmChartRef(v1alpha1.KymaComponent{Name: "monitoring"}))
+	require.Equal(t,
+		"https://github.com/kyma-project/kyma.git//resources/monitoring?ref=1.4.2",
+		helmChartRef(v1alpha1.KymaComponent{
+			Name:   "monitoring",
+			Source: &v1alpha1.ComponentSource{URL: "https://github.com/kyma-project/kyma.git//resources/monitoring?ref=1.4.2"},
+		}),
+	)
+}
+
+func Test_ComponentNamespace(t *testing.T) {
+	require.Equal(t, "kyma-system", componentNamespace(v1alpha1.KymaComponent{}))
+	require.Equal(t, "kyma-integration", componentNamespace(v1alpha1.KymaComponent{Namespace: "kyma-integration"}))
+}
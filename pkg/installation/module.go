@@ -0,0 +1,79 @@
+package installation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/kyma-project/kyma/components/installer/pkg/apis/installer/v1alpha1"
+)
+
+// Module identifies a single Kyma module requested through a repeatable
+// "--module name@version" flag. An empty Version means "use the latest
+// available version of the module".
+type Module struct {
+	Name    string
+	Version string
+}
+
+// ParseModuleFlag parses a single "--module" flag value in the "name@version"
+// or "name" form.
+func ParseModuleFlag(raw string) (Module, error) {
+	name, moduleVersion := raw, ""
+	if idx := strings.Index(raw, "@"); idx >= 0 {
+		name, moduleVersion = raw[:idx], raw[idx+1:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Module{}, fmt.Errorf("module flag %q must have the form \"name\" or \"name@version\"", raw)
+	}
+	moduleVersion = strings.TrimSpace(moduleVersion)
+	if moduleVersion != "" {
+		if _, err := semver.NewVersion(moduleVersion); err != nil {
+			return Module{}, fmt.Errorf("invalid version %q for module %q: %v", moduleVersion, name, err)
+		}
+	}
+	return Module{Name: name, Version: moduleVersion}, nil
+}
+
+// moduleSourceURL builds the source ref the Kyma Installer resolves a
+// component's chart/image from when a module is pinned to a specific
+// version, e.g. "--module monitoring@1.4.2" resolves to the monitoring
+// resources at the "1.4.2" git ref. An empty Module.Version means "latest",
+// in which case the component keeps whatever source ref the components
+// configuration already has.
+func moduleSourceURL(m Module) string {
+	return fmt.Sprintf("https://github.com/kyma-project/kyma.git//resources/%s?ref=%s", m.Name, m.Version)
+}
+
+// filterComponentsByModules narrows components down to the requested modules,
+// preserving the requested order, and resolves each pinned module's source
+// ref to the requested semver so the installer fetches that exact version
+// instead of whatever the components configuration currently points at.
+// When modules is empty, all components are returned unchanged and
+// installation/upgrade continues to apply to the whole distribution as
+// before.
+func filterComponentsByModules(components []v1alpha1.KymaComponent, modules []Module) ([]v1alpha1.KymaComponent, error) {
+	if len(modules) == 0 {
+		return components, nil
+	}
+
+	byName := make(map[string]v1alpha1.KymaComponent, len(components))
+	for _, c := range components {
+		byName[c.Name] = c
+	}
+
+	filtered := make([]v1alpha1.KymaComponent, 0, len(modules))
+	for _, m := range modules {
+		component, ok := byName[m.Name]
+		if !ok {
+			return nil, fmt.Errorf("module %q is not part of the components configuration", m.Name)
+		}
+		if m.Version != "" {
+			component.Source = &v1alpha1.ComponentSource{URL: moduleSourceURL(m)}
+		}
+		filtered = append(filtered, component)
+	}
+
+	return filtered, nil
+}
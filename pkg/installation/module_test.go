@@ -0,0 +1,46 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/kyma-project/kyma/components/installer/pkg/apis/installer/v1alpha1"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseModuleFlag(t *testing.T) {
+	m, err := ParseModuleFlag("monitoring@1.4.2")
+	require.NoError(t, err)
+	require.Equal(t, Module{Name: "monitoring", Version: "1.4.2"}, m)
+
+	m, err = ParseModuleFlag("monitoring")
+	require.NoError(t, err)
+	require.Equal(t, Module{Name: "monitoring", Version: ""}, m)
+
+	_, err = ParseModuleFlag("@1.4.2")
+	require.Error(t, err)
+
+	_, err = ParseModuleFlag("monitoring@not-a-version")
+	require.Error(t, err)
+}
+
+func Test_FilterComponentsByModules(t *testing.T) {
+	components := []v1alpha1.KymaComponent{
+		{Name: "monitoring"},
+		{Name: "logging"},
+		{Name: "istio"},
+	}
+
+	filtered, err := filterComponentsByModules(components, nil)
+	require.NoError(t, err)
+	require.Equal(t, components, filtered)
+
+	filtered, err = filterComponentsByModules(components, []Module{{Name: "logging"}, {Name: "monitoring", Version: "1.4.2"}})
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.KymaComponent{
+		{Name: "logging"},
+		{Name: "monitoring", Source: &v1alpha1.ComponentSource{URL: moduleSourceURL(Module{Name: "monitoring", Version: "1.4.2"})}},
+	}, filtered)
+
+	_, err = filterComponentsByModules(components, []Module{{Name: "missing"}})
+	require.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package installation
+
+import "time"
+
+// Options holds the configuration for an Installation: how to reach the
+// cluster, what to install or upgrade, and how intrusive the operation is
+// allowed to be.
+type Options struct {
+	// KubeconfigPath is the path to the kubeconfig file used to reach the
+	// cluster. An empty value means "use the default kubeconfig location".
+	KubeconfigPath string
+	// Timeout is the maximum time to wait for the installer/upgrade to
+	// reach a ready state.
+	Timeout time.Duration
+	// NoWait skips waiting for the installation/upgrade to finish.
+	NoWait bool
+	// CI indicates the command runs in a CI pipeline, disabling prompts.
+	CI bool
+	// NonInteractive disables prompts without implying CI.
+	NonInteractive bool
+	// Source is the Kyma source to install/upgrade to, e.g. a release
+	// version, "latest" or a commit hash.
+	Source string
+	// OverrideConfigs lists paths to override files merged into the
+	// installation/upgrade configuration.
+	OverrideConfigs []string
+	// IsLocal indicates Kyma is installed on a local cluster (e.g. k3d).
+	IsLocal bool
+	// Domain is the cluster domain used to render the default overrides.
+	Domain string
+	// TLSCert is the PEM-encoded TLS certificate used for the default
+	// overrides.
+	TLSCert string
+	// TLSKey is the PEM-encoded TLS key used for the default overrides.
+	TLSKey string
+	// Password is the admin password used for the default overrides.
+	Password string
+	// ComponentsConfig is the path to the components list (or an
+	// Installer CR) describing what to install/upgrade.
+	ComponentsConfig string
+	// FallbackLevel bounds how many commits back getLatestAvailableMasterHash
+	// is allowed to look for a usable master artifact.
+	FallbackLevel int
+	// Modules restricts an install/upgrade to the given "name" or
+	// "name@version" module flags. An empty slice means "the whole
+	// distribution", matching the ComponentsConfig file as-is.
+	Modules []string
+	// DryRun renders what would be sent to the cluster without mutating it.
+	DryRun bool
+	// DryRunDir is where DryRun output is written. An empty value prints
+	// the rendered output to stdout instead.
+	DryRunDir string
+	// Backend selects how an upgrade is applied to the cluster: through
+	// the Kyma Installer CR (BackendInstaller, the default) or as plain
+	// Helm releases (BackendHelm).
+	Backend string
+}
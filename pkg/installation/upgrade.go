@@ -22,37 +22,15 @@ func (i *Installation) UpgradeKyma() (*Result, error) {
 	}
 
 	s := i.newStep("Preparing Upgrade")
-	// Checking existence of previous installation
-	prevInstallationState, kymaVersion, err := i.checkPrevInstallation()
+	// Running the same preflight pipeline "kyma upgrade plan" uses, so the two
+	// never drift apart.
+	preflight, err := i.runUpgradePreflight(true)
 	if err != nil {
 		s.Failure()
 		return nil, err
 	}
-	logInfo, err := i.getUpgradeLogInfo(prevInstallationState, kymaVersion)
-	if err != nil {
-		s.Failure()
-		return nil, err
-	}
-
-	if prevInstallationState == "Installed" {
-		// Checking upgrade compatibility
-		if err := i.checkUpgradeCompatability(kymaVersion, version.Version); err != nil {
-			s.Failure()
-			return nil, err
-		}
-
-		// Checking migration guide
-		if err := i.promptMigrationGuide(kymaVersion, version.Version); err != nil {
-			s.Failure()
-			return nil, err
-		}
-
-		// Validating configurations
-		if err := i.validateConfigurations(); err != nil {
-			s.Failure()
-			return nil, err
-		}
 
+	if preflight.PrevInstallationState == "Installed" {
 		// Loading upgrade files
 		files, err := i.prepareFiles()
 		if err != nil {
@@ -61,23 +39,36 @@ func (i *Installation) UpgradeKyma() (*Result, error) {
 		}
 
 		// Requesting Kyma Installer to upgrade Kyma
-		if err := i.triggerUpgrade(files); err != nil {
+		if err := i.triggerUpgrade(files, preflight.KymaVersion); err != nil {
 			s.Failure()
 			return nil, err
 		}
 		s.Successf("Upgrade is ready")
 
 	} else {
-		s.Successf(logInfo)
+		s.Successf(preflight.LogInfo)
 	}
 
-	if !i.Options.NoWait {
+	prevInstallationState := preflight.PrevInstallationState
+
+	// The helm backend never touches the Installer CR, so there is no
+	// installer pod status to wait on and nothing for waitForInstaller to
+	// validate.
+	if !i.Options.NoWait && !i.Options.DryRun && i.Options.Backend != BackendHelm {
 		if prevInstallationState == "Installed" {
 			i.newStep("Waiting for upgrade to start")
 		} else {
 			i.newStep("Re-attaching installation status")
 		}
 		if err := i.waitForInstaller(); err != nil {
+			if prevInstallationState == "Installed" {
+				rollbackStep := i.newStep("Upgrade failed, rolling back to the previous snapshot")
+				if rbErr := i.RollbackUpgrade(""); rbErr != nil {
+					rollbackStep.Failure()
+					return nil, pkgErrors.Wrap(rbErr, fmt.Sprintf("upgrade failed (%s) and automatic rollback also failed", err.Error()))
+				}
+				rollbackStep.Successf("Rolled back to the previous snapshot")
+			}
 			return nil, err
 		}
 	}
@@ -108,6 +99,38 @@ func (i *Installation) getUpgradeLogInfo(prevInstallationState string, kymaVersi
 	return logInfo, nil
 }
 
+// UpgradeErrorReason identifies why an upgrade was rejected by the compatibility check.
+type UpgradeErrorReason string
+
+const (
+	// ReasonDowngrade is returned when the installed Kyma version is newer than the CLI version.
+	ReasonDowngrade UpgradeErrorReason = "Downgrade"
+	// ReasonSameVersion is returned when the installed Kyma version already matches the CLI version.
+	ReasonSameVersion UpgradeErrorReason = "SameVersion"
+	// ReasonMajorMismatch is returned when the installed Kyma version and the CLI version have different major versions.
+	ReasonMajorMismatch UpgradeErrorReason = "MajorMismatch"
+	// ReasonMinorGap is returned when the installed Kyma version is more than one minor version behind the CLI version.
+	ReasonMinorGap UpgradeErrorReason = "MinorGap"
+)
+
+// InvalidUpgradeError indicates that an upgrade from From to To is not supported.
+// It carries a machine-readable Reason so callers can branch on the failure
+// instead of matching on the rendered error string.
+type InvalidUpgradeError struct {
+	From   string
+	To     string
+	Reason UpgradeErrorReason
+	Inner  error
+}
+
+func (e *InvalidUpgradeError) Error() string {
+	return e.Inner.Error()
+}
+
+func (e *InvalidUpgradeError) Unwrap() error {
+	return e.Inner
+}
+
 func (i *Installation) checkUpgradeCompatability(kymaVersion string, cliVersion string) error {
 	kymaSemVersion, err := semver.NewVersion(kymaVersion)
 	if err != nil {
@@ -119,13 +142,33 @@ func (i *Installation) checkUpgradeCompatability(kymaVersion string, cliVersion
 	}
 
 	if kymaSemVersion.GreaterThan(cliSemVersion) {
-		return fmt.Errorf("kyma version(%s) is greater than the cli version(%s). Kyma does not support a dedicated downgrade procedure", kymaSemVersion.String(), cliSemVersion.String())
+		return &InvalidUpgradeError{
+			From:   kymaSemVersion.String(),
+			To:     cliSemVersion.String(),
+			Reason: ReasonDowngrade,
+			Inner:  fmt.Errorf("kyma version(%s) is greater than the cli version(%s). Kyma does not support a dedicated downgrade procedure", kymaSemVersion.String(), cliSemVersion.String()),
+		}
 	} else if kymaSemVersion.Equal(cliSemVersion) {
-		return fmt.Errorf("kyma version(%s) is already matching the cli version(%s)", kymaSemVersion.String(), cliSemVersion.String())
+		return &InvalidUpgradeError{
+			From:   kymaSemVersion.String(),
+			To:     cliSemVersion.String(),
+			Reason: ReasonSameVersion,
+			Inner:  fmt.Errorf("kyma version(%s) is already matching the cli version(%s)", kymaSemVersion.String(), cliSemVersion.String()),
+		}
 	} else if kymaSemVersion.Major() != cliSemVersion.Major() {
-		return fmt.Errorf("mismatch between kyma version(%s) and cli version(%s) is more than one minor version", kymaSemVersion.String(), cliSemVersion.String())
+		return &InvalidUpgradeError{
+			From:   kymaSemVersion.String(),
+			To:     cliSemVersion.String(),
+			Reason: ReasonMajorMismatch,
+			Inner:  fmt.Errorf("mismatch between kyma version(%s) and cli version(%s) is more than one minor version", kymaSemVersion.String(), cliSemVersion.String()),
+		}
 	} else if kymaSemVersion.Minor() != cliSemVersion.Minor() && kymaSemVersion.Minor()+1 != cliSemVersion.Minor() {
-		return fmt.Errorf("mismatch between kyma version(%s) and cli version(%s) is more than one minor version", kymaSemVersion.String(), cliSemVersion.String())
+		return &InvalidUpgradeError{
+			From:   kymaSemVersion.String(),
+			To:     cliSemVersion.String(),
+			Reason: ReasonMinorGap,
+			Inner:  fmt.Errorf("mismatch between kyma version(%s) and cli version(%s) is more than one minor version", kymaSemVersion.String(), cliSemVersion.String()),
+		}
 	}
 
 	// set the installation source to be the cli version
@@ -134,14 +177,16 @@ func (i *Installation) checkUpgradeCompatability(kymaVersion string, cliVersion
 	return nil
 }
 
-func (i *Installation) promptMigrationGuide(kymaVersion string, cliVersion string) error {
+// migrationGuideURL builds the URL of the migration guide for the given upgrade
+// and reports whether it actually exists (a 404 simply means no guide is needed).
+func (i *Installation) migrationGuideURL(kymaVersion string, cliVersion string) (string, bool, error) {
 	kymaSemVersion, err := semver.NewVersion(kymaVersion)
 	if err != nil {
-		return fmt.Errorf("unable to parse kyma version(%s): %v", kymaVersion, err)
+		return "", false, fmt.Errorf("unable to parse kyma version(%s): %v", kymaVersion, err)
 	}
 	cliSemVersion, err := semver.NewVersion(cliVersion)
 	if err != nil {
-		return fmt.Errorf("unable to parse cli version(%s): %v", cliVersion, err)
+		return "", false, fmt.Errorf("unable to parse cli version(%s): %v", cliVersion, err)
 	}
 
 	guideURL := fmt.Sprintf(
@@ -152,15 +197,27 @@ func (i *Installation) promptMigrationGuide(kymaVersion string, cliVersion strin
 	)
 	statusCode, err := doGet(guideURL)
 	if err != nil {
-		return fmt.Errorf("unable to check migration guide url: %v", err)
+		return "", false, fmt.Errorf("unable to check migration guide url: %v", err)
 	}
 	if statusCode == 404 {
 		// no migration guide for this release
-		i.currentStep.LogInfof("No migration guide available for %s release", cliSemVersion.String())
-		return nil
+		return guideURL, false, nil
 	}
 	if statusCode != 200 {
-		return fmt.Errorf("unexpected status code %v when checking migration guide url", statusCode)
+		return "", false, fmt.Errorf("unexpected status code %v when checking migration guide url", statusCode)
+	}
+
+	return guideURL, true, nil
+}
+
+func (i *Installation) promptMigrationGuide(kymaVersion string, cliVersion string) error {
+	guideURL, available, err := i.migrationGuideURL(kymaVersion, cliVersion)
+	if err != nil {
+		return err
+	}
+	if !available {
+		i.currentStep.LogInfof("No migration guide available for %s release", cliVersion)
+		return nil
 	}
 
 	promptMsg := fmt.Sprintf("Did you apply the migration guide? %s", guideURL)
@@ -171,12 +228,38 @@ func (i *Installation) promptMigrationGuide(kymaVersion string, cliVersion strin
 	return nil
 }
 
-func (i *Installation) triggerUpgrade(files map[string]*File) error {
+func (i *Installation) triggerUpgrade(files map[string]*File, kymaVersion string) error {
 	componentList, err := i.loadComponentsConfig()
 	if err != nil {
 		return fmt.Errorf("Could not load components configuration file. Make sure file is a valid YAML and contains component list: %s", err.Error())
 	}
 
+	if len(i.Options.Modules) > 0 {
+		modules := make([]Module, 0, len(i.Options.Modules))
+		for _, raw := range i.Options.Modules {
+			module, err := ParseModuleFlag(raw)
+			if err != nil {
+				return err
+			}
+			modules = append(modules, module)
+		}
+		componentList, err = filterComponentsByModules(componentList, modules)
+		if err != nil {
+			return fmt.Errorf("Could not resolve requested modules: %s", err.Error())
+		}
+	}
+
+	if i.Options.Backend == BackendHelm {
+		configuration, err := i.loadConfigurations(files)
+		if err != nil {
+			return pkgErrors.Wrap(err, "unable to load the configurations")
+		}
+		if i.Options.DryRun {
+			return i.writeHelmDryRunOutput(componentList, configuration)
+		}
+		return i.triggerHelmUpgrade(componentList, configuration)
+	}
+
 	i.service, err = NewInstallationServiceWithComponents(i.k8s.Config(), i.Options.Timeout, "", componentList)
 	if err != nil {
 		return fmt.Errorf("Failed to create installation service. Make sure your kubeconfig is valid: %s", err.Error())
@@ -195,6 +278,14 @@ func (i *Installation) triggerUpgrade(files map[string]*File) error {
 		return pkgErrors.Wrap(err, "unable to load the configurations")
 	}
 
+	if i.Options.DryRun {
+		return i.writeDryRunOutput(files, configuration)
+	}
+
+	if _, err := i.snapshotBeforeUpgrade(kymaVersion, version.Version, configuration); err != nil {
+		return pkgErrors.Wrap(err, "unable to snapshot the pre-upgrade state")
+	}
+
 	err = i.service.TriggerUpgrade(i.k8s.Config(), tillerFileContent, installerFileContent, installerCRFileContent, configuration)
 	if err != nil {
 		return fmt.Errorf("Failed to start upgrade: %s", err.Error())
@@ -0,0 +1,303 @@
+package installation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	installationSDK "github.com/kyma-incubator/hydroform/install/installation"
+	"github.com/kyma-project/cli/internal/kube"
+	"github.com/kyma-project/kyma/components/installer/pkg/apis/installer/v1alpha1"
+	pkgErrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// installerCRGVR is the GroupVersionResource of the Kyma Installer CR, used to
+// read back whatever is currently applied on the cluster before an upgrade.
+var installerCRGVR = schema.GroupVersionResource{
+	Group:    "installer.kyma-project.io",
+	Version:  "v1alpha1",
+	Resource: "installations",
+}
+
+const (
+	// upgradeHistoryConfigMap holds one data entry per upgrade attempt, keyed by
+	// UpgradeSnapshot.Key, so failed upgrades can be recovered without manual
+	// kubectl surgery.
+	upgradeHistoryConfigMap = "kyma-upgrade-history"
+	upgradeHistoryNamespace = "kyma-installer"
+)
+
+// UpgradeSnapshot is a recoverable capture of the Installer CR, the component
+// list and the resolved overrides used for a single upgrade attempt, taken
+// right before triggerUpgrade mutates the cluster. "kyma upgrade rollback"
+// restores the cluster to a chosen snapshot; "kyma upgrade history" lists them.
+type UpgradeSnapshot struct {
+	Key           string                        `json:"key"`
+	Timestamp     time.Time                     `json:"timestamp"`
+	FromVersion   string                        `json:"fromVersion"`
+	ToVersion     string                        `json:"toVersion"`
+	TillerCR      string                        `json:"tillerCR"`
+	InstallerYAML string                        `json:"installerYAML"`
+	InstallerCR   string                        `json:"installerCR"`
+	Components    []v1alpha1.KymaComponent      `json:"components"`
+	Configuration installationSDK.Configuration `json:"configuration"`
+}
+
+// snapshotBeforeUpgrade reads back whatever is actually running on the
+// cluster right now (the Installer CR, the Tiller/Installer deployments and
+// the component list the CR currently carries) and persists it into the
+// kyma-upgrade-history ConfigMap before triggerUpgrade calls
+// service.TriggerUpgrade. It deliberately does NOT snapshot the manifests
+// just rendered for the new version: those are what we are upgrading TO, and
+// storing them here would make "kyma upgrade rollback" re-apply the very
+// upgrade it is supposed to undo. configuration is the set of resolved
+// overrides, which come from CLI flags/override files rather than the
+// target version and so apply unchanged whichever version is installed.
+func (i *Installation) snapshotBeforeUpgrade(fromVersion, toVersion string, configuration installationSDK.Configuration) (*UpgradeSnapshot, error) {
+	current, err := i.currentClusterState()
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the current cluster state")
+	}
+
+	snapshot := &UpgradeSnapshot{
+		Key:           fmt.Sprintf("%s-%s-%s", time.Now().UTC().Format("20060102150405"), fromVersion, toVersion),
+		Timestamp:     time.Now().UTC(),
+		FromVersion:   fromVersion,
+		ToVersion:     toVersion,
+		TillerCR:      current.tillerYAML,
+		InstallerYAML: current.installerYAML,
+		InstallerCR:   current.installerCR,
+		Components:    current.components,
+		Configuration: configuration,
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to serialize upgrade snapshot")
+	}
+
+	client := i.k8s.Static().CoreV1().ConfigMaps(upgradeHistoryNamespace)
+	cm, err := client.Get(context.Background(), upgradeHistoryConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      upgradeHistoryConfigMap,
+				Namespace: upgradeHistoryNamespace,
+			},
+			Data: map[string]string{},
+		}
+		cm, err = client.Create(context.Background(), cm, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read or create the kyma-upgrade-history ConfigMap")
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[snapshot.Key] = string(data)
+
+	if _, err := client.Update(context.Background(), cm, metav1.UpdateOptions{}); err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to store upgrade snapshot")
+	}
+
+	return snapshot, nil
+}
+
+// clusterState is the subset of what's actually running on the cluster that
+// snapshotBeforeUpgrade needs to be able to restore it later.
+type clusterState struct {
+	installerCR   string
+	tillerYAML    string
+	installerYAML string
+	components    []v1alpha1.KymaComponent
+}
+
+// currentClusterState reads the Installer CR, the Tiller deployment and the
+// Installer deployment that are currently applied on the cluster, i.e. the
+// state that is about to be replaced by this upgrade.
+func (i *Installation) currentClusterState() (*clusterState, error) {
+	dynamicClient, err := dynamic.NewForConfig(i.k8s.Config())
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to create a client for the current Installer CR")
+	}
+
+	cr, err := dynamicClient.Resource(installerCRGVR).Namespace(upgradeHistoryNamespace).Get(context.Background(), "kyma-installer", metav1.GetOptions{})
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the current Installer CR")
+	}
+	installerCRYAML, err := k8syaml.Marshal(cr.Object)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to serialize the current Installer CR")
+	}
+
+	components, err := componentsFromInstallerCR(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	tillerYAML, err := i.deploymentYAML("tiller-deploy")
+	if err != nil {
+		return nil, err
+	}
+	installerYAML, err := i.deploymentYAML("kyma-installer")
+	if err != nil {
+		return nil, err
+	}
+
+	return &clusterState{
+		installerCR:   string(installerCRYAML),
+		tillerYAML:    tillerYAML,
+		installerYAML: installerYAML,
+		components:    components,
+	}, nil
+}
+
+// componentsFromInstallerCR extracts the component list the given Installer
+// CR currently carries, so a rollback restores exactly the components that
+// were applied before this upgrade rather than the ones being upgraded to.
+func componentsFromInstallerCR(cr *unstructured.Unstructured) ([]v1alpha1.KymaComponent, error) {
+	rawComponents, found, err := unstructured.NestedSlice(cr.Object, "spec", "components")
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read spec.components from the current Installer CR")
+	}
+	if !found {
+		return nil, nil
+	}
+
+	components := make([]v1alpha1.KymaComponent, 0, len(rawComponents))
+	for _, raw := range rawComponents {
+		entry, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := entry["name"].(string)
+		namespace, _ := entry["namespace"].(string)
+		components = append(components, v1alpha1.KymaComponent{Name: name, Namespace: namespace})
+	}
+	return components, nil
+}
+
+// deploymentYAML reads back a deployment that is currently running in the
+// kyma-installer namespace and renders it as YAML.
+func (i *Installation) deploymentYAML(name string) (string, error) {
+	deployment, err := i.k8s.Static().AppsV1().Deployments(upgradeHistoryNamespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", pkgErrors.Wrapf(err, "unable to read the current %s deployment", name)
+	}
+	content, err := k8syaml.Marshal(deployment)
+	if err != nil {
+		return "", pkgErrors.Wrapf(err, "unable to serialize the current %s deployment", name)
+	}
+	return string(content), nil
+}
+
+// ensureKubeClient initializes i.k8s the same way UpgradeKyma and PlanUpgrade
+// do, if it has not been initialized yet. UpgradeHistory and RollbackUpgrade
+// are valid entry points on their own (e.g. from "kyma upgrade history"), so
+// they cannot assume UpgradeKyma/PlanUpgrade already set up the client.
+func (i *Installation) ensureKubeClient() error {
+	if i.k8s != nil {
+		return nil
+	}
+	k8s, err := kube.NewFromConfigWithTimeout("", i.Options.KubeconfigPath, i.Options.Timeout)
+	if err != nil {
+		return pkgErrors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+	i.k8s = k8s
+	return nil
+}
+
+// UpgradeHistory lists all recorded upgrade snapshots, most recent first,
+// similarly to "helm history". Like UpgradeKyma and PlanUpgrade, it
+// initializes its own Kubernetes client so it can be called on its own,
+// e.g. from "kyma upgrade history" without "kyma upgrade" having run first.
+func (i *Installation) UpgradeHistory() ([]UpgradeSnapshot, error) {
+	if err := i.ensureKubeClient(); err != nil {
+		return nil, err
+	}
+
+	cm, err := i.k8s.Static().CoreV1().ConfigMaps(upgradeHistoryNamespace).Get(context.Background(), upgradeHistoryConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "unable to read the kyma-upgrade-history ConfigMap")
+	}
+
+	snapshots := make([]UpgradeSnapshot, 0, len(cm.Data))
+	for _, raw := range cm.Data {
+		var snapshot UpgradeSnapshot
+		if err := json.Unmarshal([]byte(raw), &snapshot); err != nil {
+			return nil, pkgErrors.Wrap(err, "unable to parse a stored upgrade snapshot")
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(a, b int) bool {
+		return snapshots[a].Timestamp.After(snapshots[b].Timestamp)
+	})
+
+	return snapshots, nil
+}
+
+// RollbackUpgrade restores the Installer CR and overrides from a previously
+// recorded upgrade snapshot and re-triggers the installer against it. An
+// empty key rolls back to the most recent snapshot, which is what
+// "kyma upgrade rollback" does when the user does not name one explicitly.
+// Calling UpgradeHistory first also initializes i.k8s, which the rest of
+// this function relies on.
+func (i *Installation) RollbackUpgrade(key string) error {
+	history, err := i.UpgradeHistory()
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("no upgrade snapshot is available to roll back to")
+	}
+
+	snapshot := history[0]
+	if key != "" {
+		found := false
+		for _, candidate := range history {
+			if candidate.Key == key {
+				snapshot = candidate
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no upgrade snapshot found for key %q", key)
+		}
+	}
+
+	s := i.newStep(fmt.Sprintf("Rolling back to upgrade snapshot %s (%s -> %s)", snapshot.Key, snapshot.FromVersion, snapshot.ToVersion))
+
+	i.service, err = NewInstallationServiceWithComponents(i.k8s.Config(), i.Options.Timeout, "", snapshot.Components)
+	if err != nil {
+		s.Failure()
+		return fmt.Errorf("Failed to create installation service. Make sure your kubeconfig is valid: %s", err.Error())
+	}
+
+	if err := i.service.TriggerUpgrade(i.k8s.Config(), snapshot.TillerCR, snapshot.InstallerYAML, snapshot.InstallerCR, snapshot.Configuration); err != nil {
+		s.Failure()
+		return fmt.Errorf("Failed to trigger rollback: %s", err.Error())
+	}
+
+	if err := i.k8s.WaitPodStatusByLabel("kyma-installer", "name", "kyma-installer", corev1.PodRunning); err != nil {
+		s.Failure()
+		return err
+	}
+
+	s.Successf("Rolled back to upgrade snapshot %s", snapshot.Key)
+	return nil
+}
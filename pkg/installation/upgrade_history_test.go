@@ -0,0 +1,37 @@
+package installation
+
+import (
+	"testing"
+
+	"github.com/kyma-project/kyma/components/installer/pkg/apis/installer/v1alpha1"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_ComponentsFromInstallerCR(t *testing.T) {
+	cr := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"components": []interface{}{
+					map[string]interface{}{"name": "monitoring", "namespace": "kyma-system"},
+					map[string]interface{}{"name": "istio", "namespace": "istio-system"},
+				},
+			},
+		},
+	}
+
+	components, err := componentsFromInstallerCR(cr)
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.KymaComponent{
+		{Name: "monitoring", Namespace: "kyma-system"},
+		{Name: "istio", Namespace: "istio-system"},
+	}, components)
+}
+
+func Test_ComponentsFromInstallerCR_NoComponents(t *testing.T) {
+	cr := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+
+	components, err := componentsFromInstallerCR(cr)
+	require.NoError(t, err)
+	require.Nil(t, components)
+}
@@ -0,0 +1,151 @@
+package installation
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/kyma-project/cli/cmd/kyma/version"
+	"github.com/kyma-project/cli/internal/kube"
+	pkgErrors "github.com/pkg/errors"
+)
+
+// PlanStatus describes whether an upgrade is safe to trigger.
+type PlanStatus string
+
+const (
+	// PlanStatusOK means all preflight checks passed and the upgrade can proceed.
+	PlanStatusOK PlanStatus = "OK"
+	// PlanStatusBlocked means at least one preflight check failed.
+	PlanStatusBlocked PlanStatus = "Blocked"
+)
+
+// PlanResult is the outcome of the upgrade preflight pipeline, rendered by
+// "kyma upgrade plan" without mutating the cluster.
+type PlanResult struct {
+	CurrentVersion string
+	TargetVersion  string
+	Status         PlanStatus
+	BlockingReason string
+	MigrationGuide string
+}
+
+// upgradePreflightResult is the outcome of runUpgradePreflight, the single
+// pipeline shared by UpgradeKyma and PlanUpgrade: installer state, cluster
+// reachability, semver compatibility, migration guide availability,
+// components file parsing and override validation.
+type upgradePreflightResult struct {
+	PrevInstallationState string
+	LogInfo               string
+	KymaVersion           string
+	CliVersion            string
+	MigrationGuide        string
+}
+
+// runUpgradePreflight runs the preflight pipeline shared by "kyma upgrade"
+// and "kyma upgrade plan". When interactive is true and a migration guide
+// exists, the operator is prompted to confirm it was applied, matching
+// UpgradeKyma's existing behavior; "kyma upgrade plan" always passes
+// interactive=false so it never blocks on user input and never mutates the
+// cluster. A non-nil error means the upgrade must abort. If reading the
+// installer state or its log info fails, result is nil; for any later
+// failing step, result is still populated with whatever was determined
+// before that step, so PlanUpgrade can still render a blocked plan.
+func (i *Installation) runUpgradePreflight(interactive bool) (*upgradePreflightResult, error) {
+	prevInstallationState, kymaVersion, err := i.checkPrevInstallation()
+	if err != nil {
+		return nil, err
+	}
+	logInfo, err := i.getUpgradeLogInfo(prevInstallationState, kymaVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &upgradePreflightResult{
+		PrevInstallationState: prevInstallationState,
+		LogInfo:               logInfo,
+		KymaVersion:           kymaVersion,
+		CliVersion:            version.Version,
+	}
+
+	if prevInstallationState != "Installed" {
+		return result, nil
+	}
+
+	if err := i.checkUpgradeCompatability(kymaVersion, version.Version); err != nil {
+		return result, err
+	}
+
+	if interactive {
+		if err := i.promptMigrationGuide(kymaVersion, version.Version); err != nil {
+			return result, err
+		}
+	} else {
+		guideURL, available, err := i.migrationGuideURL(kymaVersion, version.Version)
+		if err != nil {
+			return result, err
+		}
+		if available {
+			result.MigrationGuide = guideURL
+		}
+	}
+
+	if err := i.validateConfigurations(); err != nil {
+		return result, err
+	}
+
+	if _, err := i.loadComponentsConfig(); err != nil {
+		return result, fmt.Errorf("Could not load components configuration file. Make sure file is a valid YAML and contains component list: %s", err.Error())
+	}
+
+	return result, nil
+}
+
+// PlanUpgrade runs the same preflight pipeline as UpgradeKyma but never
+// calls the installer; it is the backend of "kyma upgrade plan".
+func (i *Installation) PlanUpgrade() (*PlanResult, error) {
+	var err error
+	if i.k8s, err = kube.NewFromConfigWithTimeout("", i.Options.KubeconfigPath, i.Options.Timeout); err != nil {
+		return nil, pkgErrors.Wrap(err, "Could not initialize the Kubernetes client. Make sure your kubeconfig is valid")
+	}
+
+	preflight, preflightErr := i.runUpgradePreflight(false)
+	if preflight == nil {
+		return nil, preflightErr
+	}
+
+	result := &PlanResult{
+		CurrentVersion: preflight.KymaVersion,
+		TargetVersion:  preflight.CliVersion,
+		Status:         PlanStatusOK,
+		MigrationGuide: preflight.MigrationGuide,
+	}
+
+	if preflight.PrevInstallationState != "Installed" {
+		result.Status = PlanStatusBlocked
+		result.BlockingReason = fmt.Sprintf("installer is not in an upgradable state (%s)", preflight.PrevInstallationState)
+		return result, nil
+	}
+
+	if preflightErr != nil {
+		result.Status = PlanStatusBlocked
+		result.BlockingReason = preflightErr.Error()
+	}
+
+	return result, nil
+}
+
+// PrintPlan renders a PlanResult as a table, following the same
+// "current -> target" layout "kyma upgrade" already prints to the step log.
+func PrintPlan(w io.Writer, result *PlanResult) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CURRENT VERSION\tTARGET VERSION\tSTATUS\tBLOCKING REASON\tMIGRATION GUIDE")
+	fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", result.CurrentVersion, result.TargetVersion, result.Status, result.BlockingReason, result.MigrationGuide)
+	tw.Flush()
+}
+
+// PrintPlanToStdout is a convenience wrapper for PrintPlan using os.Stdout.
+func PrintPlanToStdout(result *PlanResult) {
+	PrintPlan(os.Stdout, result)
+}
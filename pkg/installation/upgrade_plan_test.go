@@ -0,0 +1,27 @@
+package installation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PrintPlan(t *testing.T) {
+	result := &PlanResult{
+		CurrentVersion: "1.3.0",
+		TargetVersion:  "1.4.0",
+		Status:         PlanStatusBlocked,
+		BlockingReason: "installer is not in an upgradable state (InProgress)",
+	}
+
+	var buf bytes.Buffer
+	PrintPlan(&buf, result)
+
+	out := buf.String()
+	require.Contains(t, out, "CURRENT VERSION")
+	require.Contains(t, out, "1.3.0")
+	require.Contains(t, out, "1.4.0")
+	require.Contains(t, out, string(PlanStatusBlocked))
+	require.Contains(t, out, result.BlockingReason)
+}
@@ -0,0 +1,34 @@
+package installation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckUpgradeCompatability(t *testing.T) {
+	testData := []struct {
+		testName    string
+		kymaVersion string
+		cliVersion  string
+		wantReason  UpgradeErrorReason
+	}{
+		{testName: "downgrade", kymaVersion: "1.5.0", cliVersion: "1.4.0", wantReason: ReasonDowngrade},
+		{testName: "same version", kymaVersion: "1.4.0", cliVersion: "1.4.0", wantReason: ReasonSameVersion},
+		{testName: "major mismatch", kymaVersion: "1.4.0", cliVersion: "2.4.0", wantReason: ReasonMajorMismatch},
+		{testName: "minor gap", kymaVersion: "1.2.0", cliVersion: "1.4.0", wantReason: ReasonMinorGap},
+	}
+
+	for _, tt := range testData {
+		i := &Installation{Options: &Options{}}
+		err := i.checkUpgradeCompatability(tt.kymaVersion, tt.cliVersion)
+		require.Error(t, err, tt.testName)
+
+		var upgradeErr *InvalidUpgradeError
+		require.True(t, errors.As(err, &upgradeErr), tt.testName)
+		require.Equal(t, tt.wantReason, upgradeErr.Reason, tt.testName)
+		require.Equal(t, tt.kymaVersion, upgradeErr.From, tt.testName)
+		require.Equal(t, tt.cliVersion, upgradeErr.To, tt.testName)
+	}
+}